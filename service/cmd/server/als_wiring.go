@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/RohanSi4/MovieReccomenderSystem/service/jobs"
+	"github.com/RohanSi4/MovieReccomenderSystem/service/models/als"
+)
+
+// loadALSFactors loads previously-trained ALS factors from disk, if any.
+// Missing factors just mean personalized ranking falls back to the
+// heuristic-only score until /admin/train has run at least once.
+func (a *App) loadALSFactors() {
+	factors, err := als.Load(filepath.Join(a.DataDir, alsFactorsFile))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("load als factors: %v", err)
+		}
+		return
+	}
+	a.alsPtr.Store(factors)
+	log.Printf("loaded ALS factors (%d users, %d items, d=%d)", len(factors.UserIndex), len(factors.ItemIndex), factors.D)
+}
+
+// trainALS retrains ALS factors from ratings.csv and swaps them in.
+func (a *App) trainALS() error {
+	path := filepath.Join(a.DataDir, "ratings.csv")
+	ratings, err := loadRatingsCSV(path)
+	if err != nil {
+		return err
+	}
+	if len(ratings) == 0 {
+		return fmt.Errorf("no ratings found in %s", path)
+	}
+
+	factors := als.Train(ratings, als.DefaultConfig())
+	a.alsPtr.Store(factors)
+
+	return als.Save(filepath.Join(a.DataDir, alsFactorsFile), factors)
+}
+
+// handleAdminTrain kicks off an ALS retrain as a background job.
+func (a *App) handleAdminTrain(w http.ResponseWriter, r *http.Request) {
+	setCORS(w)
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if a.Jobs == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "job queue disabled"})
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "POST required"})
+		return
+	}
+
+	rec, err := a.Jobs.Enqueue(jobs.KindRetrainWeights, map[string]string{})
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusAccepted, rec)
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// enrichmentCacheFile is a sidecar cache of TMDB fields keyed by MovieID,
+// kept separate from movie_features.csv so the CSV stays a clean snapshot
+// of the MovieLens export.
+const enrichmentCacheFile = "tmdb_cache.json"
+
+// cachedMovieDetails is the subset of Movie populated from TMDB, persisted
+// to enrichmentCacheFile so a restart doesn't have to re-fetch everything.
+type cachedMovieDetails struct {
+	TMDBVoteAvg    float64   `json:"tmdb_vote_avg"`
+	TMDBPopularity float64   `json:"tmdb_popularity"`
+	TMDBGenres     string    `json:"tmdb_genres"`
+	TMDBPosterPath string    `json:"tmdb_poster_path"`
+	TMDBOverview   string    `json:"tmdb_overview"`
+	TMDBRelease    string    `json:"tmdb_release_date"`
+	FetchedAt      time.Time `json:"fetched_at"`
+}
+
+// needsEnrichment reports whether m is missing the fields TMDB supplies.
+func needsEnrichment(m Movie) bool {
+	return m.TMDBVoteAvg == 0 || m.TMDBPopularity == 0 || strings.TrimSpace(m.TMDBGenres) == ""
+}
+
+// enqueueStaleMovies scans MoviesByID for entries that are missing TMDB
+// fields or whose cached data has gone stale and schedules them for
+// background refresh.
+func (a *App) enqueueStaleMovies() {
+	a.enrichMu.Lock()
+	enrichedAt := make(map[int]time.Time, len(a.enrichedAt))
+	for id, t := range a.enrichedAt {
+		enrichedAt[id] = t
+	}
+	a.enrichMu.Unlock()
+
+	queued := 0
+	for _, m := range a.movies() {
+		stale := time.Since(enrichedAt[m.MovieID]) > enrichStaleAfter
+		if !needsEnrichment(m) && !stale {
+			continue
+		}
+		select {
+		case a.enrichQueue <- m.MovieID:
+			queued++
+		default:
+			log.Printf("enrichment queue full; dropping movie %d", m.MovieID)
+		}
+	}
+	if queued > 0 {
+		log.Printf("queued %d movies for TMDB enrichment", queued)
+	}
+}
+
+// startEnrichmentWorkers launches n goroutines that drain enrichQueue and
+// fetch+apply TMDB data for each movie ID, one at a time per worker (the
+// shared TMDB client's token bucket is what actually bounds throughput).
+func (a *App) startEnrichmentWorkers(n int) {
+	for i := 0; i < n; i++ {
+		go func() {
+			for id := range a.enrichQueue {
+				if err := a.enrichMovie(context.Background(), id); err != nil {
+					log.Printf("enrich movie %d: %v", id, err)
+				}
+			}
+		}()
+	}
+}
+
+// enrichMovie fetches live TMDB data for id and applies it in-memory,
+// persisting the result to the sidecar cache file.
+func (a *App) enrichMovie(ctx context.Context, id int) error {
+	details, err := a.TMDB.Movie(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	genreNames := make([]string, 0, len(details.Genres))
+	for _, g := range details.Genres {
+		genreNames = append(genreNames, g.Name)
+	}
+
+	cached := cachedMovieDetails{
+		TMDBVoteAvg:    details.VoteAverage,
+		TMDBPopularity: details.Popularity,
+		TMDBGenres:     strings.Join(genreNames, "|"),
+		TMDBPosterPath: details.PosterPath,
+		TMDBOverview:   details.Overview,
+		TMDBRelease:    details.ReleaseDate,
+		FetchedAt:      time.Now(),
+	}
+	a.applyEnrichment(id, cached)
+	return a.saveEnrichmentCache()
+}
+
+// applyEnrichment updates the in-memory Movie for id with cached's fields.
+// It builds a new catalog snapshot and swaps it in rather than mutating
+// the current one in place, so concurrent readers (HTTP handlers) never
+// observe a half-written Movie and never need to take a lock. enrichMu
+// only serializes concurrent writers against each other.
+func (a *App) applyEnrichment(id int, cached cachedMovieDetails) {
+	a.enrichMu.Lock()
+	defer a.enrichMu.Unlock()
+
+	current := a.catalog.Load()
+	if current == nil {
+		return
+	}
+	m, ok := current.byID[id]
+	if !ok {
+		return
+	}
+	m.TMDBVoteAvg = cached.TMDBVoteAvg
+	m.TMDBPopularity = cached.TMDBPopularity
+	m.TMDBGenres = cached.TMDBGenres
+	m.TMDBPosterPath = cached.TMDBPosterPath
+	m.TMDBOverview = cached.TMDBOverview
+	m.TMDBRelease = cached.TMDBRelease
+
+	byID := make(map[int]Movie, len(current.byID))
+	for k, v := range current.byID {
+		byID[k] = v
+	}
+	byID[id] = m
+
+	movies := append([]Movie(nil), current.movies...)
+	for i := range movies {
+		if movies[i].MovieID == id {
+			movies[i] = m
+			break
+		}
+	}
+
+	a.catalog.Store(&catalogData{movies: movies, byID: byID})
+	a.enrichedAt[id] = cached.FetchedAt
+}
+
+func (a *App) enrichmentCachePath() string {
+	return filepath.Join(a.DataDir, enrichmentCacheFile)
+}
+
+// loadEnrichmentCache merges any previously-fetched TMDB data back into
+// MoviesByID/Movies on startup.
+func (a *App) loadEnrichmentCache() {
+	data, err := os.ReadFile(a.enrichmentCachePath())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("read tmdb cache: %v", err)
+		}
+		return
+	}
+
+	var entries map[int]cachedMovieDetails
+	if err := json.Unmarshal(data, &entries); err != nil {
+		log.Printf("parse tmdb cache: %v", err)
+		return
+	}
+	for id, cached := range entries {
+		a.applyEnrichment(id, cached)
+	}
+	log.Printf("loaded %d cached TMDB entries", len(entries))
+}
+
+// saveEnrichmentCache writes the full enrichedAt/MoviesByID TMDB fields
+// back to the sidecar cache file.
+func (a *App) saveEnrichmentCache() error {
+	a.enrichMu.Lock()
+	current := a.catalog.Load()
+	entries := make(map[int]cachedMovieDetails, len(a.enrichedAt))
+	for id, fetchedAt := range a.enrichedAt {
+		if current == nil {
+			continue
+		}
+		m, ok := current.byID[id]
+		if !ok {
+			continue
+		}
+		entries[id] = cachedMovieDetails{
+			TMDBVoteAvg:    m.TMDBVoteAvg,
+			TMDBPopularity: m.TMDBPopularity,
+			TMDBGenres:     m.TMDBGenres,
+			TMDBPosterPath: m.TMDBPosterPath,
+			TMDBOverview:   m.TMDBOverview,
+			TMDBRelease:    m.TMDBRelease,
+			FetchedAt:      fetchedAt,
+		}
+	}
+	a.enrichMu.Unlock()
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(a.enrichmentCachePath(), data, 0o644)
+}
+
+// handleAdminRefresh forces a refresh of a single title, bypassing the
+// staleness check. Intended for operator use, not the public client.
+func (a *App) handleAdminRefresh(w http.ResponseWriter, r *http.Request) {
+	setCORS(w)
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if a.TMDB == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "tmdb enrichment disabled"})
+		return
+	}
+
+	idParam := r.URL.Query().Get("movie_id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "movie_id required"})
+		return
+	}
+	if _, ok := a.movieByID(id); !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "movie not found"})
+		return
+	}
+
+	if err := a.enrichMovie(r.Context(), id); err != nil {
+		writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return
+	}
+	movie, _ := a.movieByID(id)
+	writeJSON(w, http.StatusOK, movie)
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// EventKind enumerates the implicit-feedback signals the client can send
+// via POST /events.
+type EventKind string
+
+const (
+	EventView    EventKind = "view"
+	EventClick   EventKind = "click"
+	EventLike    EventKind = "like"
+	EventDismiss EventKind = "dismiss"
+)
+
+// Event is one user interaction recorded via POST /events.
+type Event struct {
+	UserID  int       `json:"user_id"`
+	MovieID int       `json:"movie_id"`
+	Kind    EventKind `json:"kind"`
+	TS      int64     `json:"ts"`
+}
+
+const (
+	eventsCSVFile = "events.csv"
+
+	// eventRingBufferSize bounds how many recent events are kept per user
+	// in memory.
+	eventRingBufferSize = 200
+
+	// eventFlushInterval is how often buffered events are appended to
+	// eventsCSVFile.
+	eventFlushInterval = 5 * time.Second
+
+	// recentGenreHalfLife controls how fast a genre's recent-activity
+	// weight decays: it halves every half-life.
+	recentGenreHalfLife = 14 * 24 * time.Hour
+
+	// recentDismissWindow is how far back a "dismiss" event still
+	// excludes a movie from rankings.
+	recentDismissWindow = 30 * 24 * time.Hour
+)
+
+// userEventBuffer is a bounded, append-only log of a single user's recent
+// events, trimmed to eventRingBufferSize.
+type userEventBuffer struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (b *userEventBuffer) add(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.events = append(b.events, e)
+	if len(b.events) > eventRingBufferSize {
+		b.events = b.events[len(b.events)-eventRingBufferSize:]
+	}
+}
+
+func (b *userEventBuffer) snapshot() []Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	out := make([]Event, len(b.events))
+	copy(out, b.events)
+	return out
+}
+
+// handleEvents records a single implicit-feedback event.
+func (a *App) handleEvents(w http.ResponseWriter, r *http.Request) {
+	setCORS(w)
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "POST required"})
+		return
+	}
+
+	var e Event
+	if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid json"})
+		return
+	}
+	if e.UserID <= 0 || e.MovieID <= 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "user_id and movie_id required"})
+		return
+	}
+	switch e.Kind {
+	case EventView, EventClick, EventLike, EventDismiss:
+	default:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid kind"})
+		return
+	}
+	if e.TS == 0 {
+		e.TS = time.Now().Unix()
+	}
+
+	a.recordEvent(e)
+	writeJSON(w, http.StatusAccepted, map[string]string{"status": "recorded"})
+}
+
+// recordEvent appends e to its user's in-memory ring buffer and queues it
+// for the next periodic CSV flush.
+func (a *App) recordEvent(e Event) {
+	a.eventsMu.Lock()
+	buf, ok := a.eventBuffers[e.UserID]
+	if !ok {
+		buf = &userEventBuffer{}
+		a.eventBuffers[e.UserID] = buf
+	}
+	a.eventsMu.Unlock()
+
+	buf.add(e)
+
+	a.flushMu.Lock()
+	a.pendingEvents = append(a.pendingEvents, e)
+	a.flushMu.Unlock()
+}
+
+// recentlyDismissed returns the set of movie IDs user dismissed within
+// recentDismissWindow, so rankMovies can exclude them.
+func (a *App) recentlyDismissed(user *UserFeatures) map[int]bool {
+	dismissed := map[int]bool{}
+	if user == nil {
+		return dismissed
+	}
+
+	a.eventsMu.Lock()
+	buf, ok := a.eventBuffers[user.UserID]
+	a.eventsMu.Unlock()
+	if !ok {
+		return dismissed
+	}
+
+	cutoff := time.Now().Add(-recentDismissWindow)
+	for _, e := range buf.snapshot() {
+		if e.Kind == EventDismiss && time.Unix(e.TS, 0).After(cutoff) {
+			dismissed[e.MovieID] = true
+		}
+	}
+	return dismissed
+}
+
+// startEventFlusher launches a background goroutine that periodically
+// appends buffered events to eventsCSVFile.
+func (a *App) startEventFlusher() {
+	ticker := time.NewTicker(eventFlushInterval)
+	go func() {
+		for range ticker.C {
+			a.flushEvents()
+		}
+	}()
+}
+
+func (a *App) flushEvents() {
+	a.flushMu.Lock()
+	pending := a.pendingEvents
+	a.pendingEvents = nil
+	a.flushMu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	path := filepath.Join(a.DataDir, eventsCSVFile)
+	writeHeader := true
+	if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+		writeHeader = false
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		log.Printf("flush events: %v", err)
+		return
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	if writeHeader {
+		_ = w.Write([]string{"user_id", "movie_id", "kind", "ts"})
+	}
+	for _, e := range pending {
+		_ = w.Write([]string{
+			strconv.Itoa(e.UserID),
+			strconv.Itoa(e.MovieID),
+			string(e.Kind),
+			strconv.FormatInt(e.TS, 10),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		log.Printf("flush events: %v", err)
+	}
+}
+
+// loadRecentGenres computes each user's decayed recent-genre weights from
+// events.csv and attaches them to UsersByID. Called once at startup;
+// events recorded afterward only affect ranking via the in-memory ring
+// buffer until the next restart.
+func (a *App) loadRecentGenres() {
+	events, err := loadEventsCSV(filepath.Join(a.DataDir, eventsCSVFile))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("load events: %v", err)
+		}
+		return
+	}
+
+	now := time.Now()
+	byUser := make(map[int]map[string]float64)
+	for _, e := range events {
+		weight := eventKindWeight(e.Kind)
+		if weight <= 0 {
+			continue
+		}
+		m, ok := a.movieByID(e.MovieID)
+		if !ok {
+			continue
+		}
+		weight *= decayFactor(now.Sub(time.Unix(e.TS, 0)))
+		if weight <= 0 {
+			continue
+		}
+
+		genres := byUser[e.UserID]
+		if genres == nil {
+			genres = make(map[string]float64)
+			byUser[e.UserID] = genres
+		}
+		for g := range parseGenres(preferGenres(m)) {
+			genres[g] += weight
+		}
+	}
+
+	for uid, genres := range byUser {
+		u, ok := a.UsersByID[uid]
+		if !ok {
+			u = UserFeatures{UserID: uid}
+		}
+		u.RecentGenres = genres
+		a.UsersByID[uid] = u
+	}
+	log.Printf("computed recent-genre signals for %d users from %d events", len(byUser), len(events))
+}
+
+// eventKindWeight is how strongly each event kind signals genre interest.
+// Dismiss isn't here: it never boosts a genre, it only excludes a movie.
+func eventKindWeight(kind EventKind) float64 {
+	switch kind {
+	case EventLike:
+		return 1.0
+	case EventClick:
+		return 0.5
+	case EventView:
+		return 0.2
+	default:
+		return 0
+	}
+}
+
+// decayFactor is the exponential decay applied to an event's weight based
+// on its age, halving every recentGenreHalfLife.
+func decayFactor(age time.Duration) float64 {
+	if age < 0 {
+		age = 0
+	}
+	return math.Pow(0.5, float64(age)/float64(recentGenreHalfLife))
+}
+
+func loadEventsCSV(path string) ([]Event, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, err
+	}
+	idx := headerIndex(header)
+
+	required := []string{"user_id", "movie_id", "kind", "ts"}
+	for _, col := range required {
+		if _, ok := idx[col]; !ok {
+			return nil, fmt.Errorf("missing column %s in %s", col, path)
+		}
+	}
+
+	var events []Event
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if err == csv.ErrFieldCount {
+				continue
+			}
+			return nil, err
+		}
+
+		events = append(events, Event{
+			UserID:  parseInt(row, idx, "user_id"),
+			MovieID: parseInt(row, idx, "movie_id"),
+			Kind:    EventKind(parseString(row, idx, "kind")),
+			TS:      int64(parseInt(row, idx, "ts")),
+		})
+	}
+
+	return events, nil
+}
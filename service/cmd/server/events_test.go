@@ -0,0 +1,62 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDecayFactorHalvesEveryHalfLife(t *testing.T) {
+	if got := decayFactor(0); math.Abs(got-1.0) > 1e-9 {
+		t.Fatalf("decayFactor(0) = %f, want 1.0", got)
+	}
+	if got := decayFactor(recentGenreHalfLife); math.Abs(got-0.5) > 1e-9 {
+		t.Fatalf("decayFactor(halfLife) = %f, want 0.5", got)
+	}
+	if got := decayFactor(2 * recentGenreHalfLife); math.Abs(got-0.25) > 1e-9 {
+		t.Fatalf("decayFactor(2*halfLife) = %f, want 0.25", got)
+	}
+}
+
+func TestDecayFactorClampsNegativeAge(t *testing.T) {
+	if got := decayFactor(-time.Hour); got != 1.0 {
+		t.Fatalf("decayFactor(negative age) = %f, want 1.0", got)
+	}
+}
+
+func TestEventKindWeightOrderingAndDismissExcluded(t *testing.T) {
+	if !(eventKindWeight(EventLike) > eventKindWeight(EventClick) && eventKindWeight(EventClick) > eventKindWeight(EventView)) {
+		t.Fatalf("expected like > click > view, got like=%f click=%f view=%f",
+			eventKindWeight(EventLike), eventKindWeight(EventClick), eventKindWeight(EventView))
+	}
+	if got := eventKindWeight(EventDismiss); got != 0 {
+		t.Fatalf("eventKindWeight(dismiss) = %f, want 0 (dismiss only excludes, never boosts a genre)", got)
+	}
+}
+
+func TestRecentGenreOverlapSumsMatchingGenres(t *testing.T) {
+	m := Movie{Genres: "Action|Sci-Fi"}
+	recent := map[string]float64{"action": 0.6, "comedy": 0.9}
+
+	if got := recentGenreOverlap(m, recent); math.Abs(got-0.6) > 1e-9 {
+		t.Fatalf("recentGenreOverlap = %f, want 0.6 (only the action weight should count)", got)
+	}
+}
+
+func TestRecentlyDismissedExcludesOnlyWithinWindow(t *testing.T) {
+	app := &App{eventBuffers: make(map[int]*userEventBuffer)}
+	buf := &userEventBuffer{}
+	now := time.Now()
+	buf.add(Event{UserID: 1, MovieID: 100, Kind: EventDismiss, TS: now.Unix()})
+	buf.add(Event{UserID: 1, MovieID: 200, Kind: EventDismiss, TS: now.Add(-2 * recentDismissWindow).Unix()})
+	app.eventBuffers[1] = buf
+
+	dismissed := app.recentlyDismissed(&UserFeatures{UserID: 1})
+
+	if !dismissed[100] {
+		t.Fatal("a recent dismiss should exclude the movie")
+	}
+	if dismissed[200] {
+		t.Fatal("a dismiss older than recentDismissWindow should not exclude the movie")
+	}
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/RohanSi4/MovieReccomenderSystem/service/jobs"
+)
+
+// jobsStoreFile is where job records are persisted between restarts.
+const jobsStoreFile = "jobs.json"
+
+// jobWorkerCount is the size of the job queue's worker pool.
+const jobWorkerCount = 4
+
+// jobMaxRetries is how many attempts a job gets in total (the first run
+// plus retries) before it's marked failed for good.
+const jobMaxRetries = 3
+
+// initJobs wires up the job queue: a Store on disk, factories for every
+// supported Kind, and a pool of workers to drain it.
+func (a *App) initJobs() error {
+	store, err := jobs.NewStore(filepath.Join(a.DataDir, jobsStoreFile))
+	if err != nil {
+		return err
+	}
+
+	a.jobResults = make(map[string]any)
+	a.Jobs = jobs.NewQueue(store, map[jobs.Kind]jobs.Factory{
+		jobs.KindEnrichMovie:          a.newEnrichMovieJob,
+		jobs.KindPosterDownload:       a.newPosterDownloadJob,
+		jobs.KindPrecomputeSimilarity: a.newPrecomputeSimilarityJob,
+		jobs.KindRetrainWeights:       a.newRetrainWeightsJob,
+		jobs.KindPrecomputeTopK:       a.newPrecomputeTopKJob,
+	}, jobMaxRetries)
+
+	a.Jobs.StartWorkers(context.Background(), jobWorkerCount)
+	return nil
+}
+
+// jobsCreateRequest is the body accepted by POST /jobs.
+type jobsCreateRequest struct {
+	Kind    jobs.Kind       `json:"kind"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// handleJobsCreate enqueues enrichment, precompute-similarity,
+// poster-download, and retrain-weights jobs.
+func (a *App) handleJobsCreate(w http.ResponseWriter, r *http.Request) {
+	setCORS(w)
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if a.Jobs == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "job queue disabled"})
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "POST required"})
+		return
+	}
+
+	var req jobsCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid json"})
+		return
+	}
+
+	rec, err := a.Jobs.Enqueue(req.Kind, req.Payload)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusAccepted, rec)
+}
+
+// handleJobsGet serves GET /jobs/{id}, including the job's result once
+// done if the job kind publishes one.
+func (a *App) handleJobsGet(w http.ResponseWriter, r *http.Request) {
+	setCORS(w)
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if a.Jobs == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "job queue disabled"})
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "GET required"})
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/jobs/")
+	if id == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "job id required"})
+		return
+	}
+	rec, ok := a.Jobs.Get(id)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "job not found"})
+		return
+	}
+
+	resp := map[string]any{"job": rec}
+	if result, ok := a.jobResult(id); ok {
+		resp["result"] = result
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (a *App) storeJobResult(id string, result any) {
+	a.jobResultsMu.Lock()
+	defer a.jobResultsMu.Unlock()
+	a.jobResults[id] = result
+}
+
+func (a *App) jobResult(id string) (any, bool) {
+	a.jobResultsMu.Lock()
+	defer a.jobResultsMu.Unlock()
+	result, ok := a.jobResults[id]
+	return result, ok
+}
+
+// --- enrich_movie --------------------------------------------------------
+
+type enrichMovieJob struct {
+	app     *App
+	movieID int
+}
+
+func (a *App) newEnrichMovieJob(id string, payload json.RawMessage) (jobs.Job, error) {
+	var p struct {
+		MovieID int `json:"movie_id"`
+	}
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, err
+	}
+	return &enrichMovieJob{app: a, movieID: p.MovieID}, nil
+}
+
+func (j *enrichMovieJob) Kind() jobs.Kind { return jobs.KindEnrichMovie }
+
+func (j *enrichMovieJob) Run(ctx context.Context) error {
+	if j.app.TMDB == nil {
+		return fmt.Errorf("tmdb enrichment disabled")
+	}
+	return j.app.enrichMovie(ctx, j.movieID)
+}
+
+// --- poster_download ------------------------------------------------------
+
+type posterDownloadJob struct {
+	app     *App
+	movieID int
+}
+
+func (a *App) newPosterDownloadJob(id string, payload json.RawMessage) (jobs.Job, error) {
+	var p struct {
+		MovieID int `json:"movie_id"`
+	}
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, err
+	}
+	return &posterDownloadJob{app: a, movieID: p.MovieID}, nil
+}
+
+func (j *posterDownloadJob) Kind() jobs.Kind { return jobs.KindPosterDownload }
+
+// Run downloads the movie's poster image into a local posters/ cache
+// directory so the frontend can be served from disk instead of TMDB's CDN.
+func (j *posterDownloadJob) Run(ctx context.Context) error {
+	movie, ok := j.app.movieByID(j.movieID)
+	if !ok {
+		return fmt.Errorf("movie %d not found", j.movieID)
+	}
+	if movie.TMDBPosterPath == "" {
+		return fmt.Errorf("movie %d has no poster path", j.movieID)
+	}
+
+	url := joinPosterURL(j.app.PosterBase, movie.TMDBPosterPath)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("poster download returned status %d", resp.StatusCode)
+	}
+
+	dir := filepath.Join(j.app.DataDir, "posters")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	dest := filepath.Join(dir, strings.TrimPrefix(movie.TMDBPosterPath, "/"))
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(f, resp.Body)
+	return err
+}
+
+// --- precompute_similarity --------------------------------------------------
+
+type precomputeSimilarityJob struct{ app *App }
+
+func (a *App) newPrecomputeSimilarityJob(id string, payload json.RawMessage) (jobs.Job, error) {
+	return &precomputeSimilarityJob{app: a}, nil
+}
+
+func (j *precomputeSimilarityJob) Kind() jobs.Kind { return jobs.KindPrecomputeSimilarity }
+
+// Run rebuilds the item-item similarity index from the current in-memory
+// catalog, picking up any enrichment that has landed since the last build.
+func (j *precomputeSimilarityJob) Run(ctx context.Context) error {
+	// The cache key only tracks movie_features.csv, so drop the cached index
+	// first; otherwise an unchanged CSV would just reload the old index.
+	if err := os.Remove(filepath.Join(j.app.DataDir, similarityCacheFile)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	j.app.buildSimilarityIndex()
+	return nil
+}
+
+// --- retrain_weights ---------------------------------------------------------
+
+type retrainWeightsJob struct{ app *App }
+
+func (a *App) newRetrainWeightsJob(id string, payload json.RawMessage) (jobs.Job, error) {
+	return &retrainWeightsJob{app: a}, nil
+}
+
+func (j *retrainWeightsJob) Kind() jobs.Kind { return jobs.KindRetrainWeights }
+
+func (j *retrainWeightsJob) Run(ctx context.Context) error {
+	return j.app.trainALS()
+}
+
+// --- precompute_topk -------------------------------------------------------
+
+type precomputeTopKJob struct {
+	app    *App
+	jobID  string
+	userID int
+	k      int
+}
+
+func (a *App) newPrecomputeTopKJob(id string, payload json.RawMessage) (jobs.Job, error) {
+	var p struct {
+		UserID int `json:"user_id"`
+		K      int `json:"k"`
+	}
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, err
+	}
+	return &precomputeTopKJob{app: a, jobID: id, userID: p.UserID, k: p.K}, nil
+}
+
+func (j *precomputeTopKJob) Kind() jobs.Kind { return jobs.KindPrecomputeTopK }
+
+// Run computes a personalized top-K ranking for userID and publishes it so
+// GET /jobs/{id} can return it once the job is done.
+func (j *precomputeTopKJob) Run(ctx context.Context) error {
+	var userPtr *UserFeatures
+	if user, ok := j.app.UsersByID[j.userID]; ok {
+		userPtr = &user
+	}
+	k := j.k
+	if k <= 0 {
+		k = 25
+	}
+	results := j.app.rankMovies(userPtr, k, defaultMMRLambda, defaultMMRPoolSize)
+	j.app.storeJobResult(j.jobID, results)
+	return nil
+}
+
+// enqueuePrecomputeTopK enqueues a precompute_topk job for userID and
+// returns its Record.
+func (a *App) enqueuePrecomputeTopK(userID, k int) (*jobs.Record, error) {
+	if a.Jobs == nil {
+		return nil, fmt.Errorf("job queue disabled")
+	}
+	return a.Jobs.Enqueue(jobs.KindPrecomputeTopK, map[string]int{"user_id": userID, "k": k})
+}
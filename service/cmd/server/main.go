@@ -13,7 +13,14 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/RohanSi4/MovieReccomenderSystem/service/jobs"
+	"github.com/RohanSi4/MovieReccomenderSystem/service/models/als"
+	"github.com/RohanSi4/MovieReccomenderSystem/service/similarity"
+	"github.com/RohanSi4/MovieReccomenderSystem/service/tmdb"
 )
 
 type Movie struct {
@@ -34,29 +41,141 @@ type UserFeatures struct {
 	UserID      int
 	RatingMean  float64
 	RatingCount int
+	// RecentGenres is an exponentially-decayed weight per genre, computed
+	// from the user's recent view/click/like events, used to bias ranking
+	// toward what they've been engaging with lately.
+	RecentGenres map[string]float64
+}
+
+// catalogData is an immutable snapshot of the movie catalog. Readers load
+// the current snapshot with App.movies/App.movieByID without taking a
+// lock; writers (enrichment) build a new snapshot and swap it in via
+// App.catalog, serialized by enrichMu so concurrent enrichments don't
+// clobber each other's copy.
+type catalogData struct {
+	movies []Movie
+	byID   map[int]Movie
 }
 
 type App struct {
-	Movies       []Movie
-	MoviesByID   map[int]Movie
+	catalog      atomic.Pointer[catalogData]
 	UsersByID    map[int]UserFeatures
 	DataDir      string
 	PosterBase   string
 	ScoreWeights ScoreWeights
+
+	TMDB        *tmdb.Client
+	enrichQueue chan int
+	enrichMu    sync.Mutex
+	enrichedAt  map[int]time.Time
+
+	Jobs         *jobs.Queue
+	jobResultsMu sync.Mutex
+	jobResults   map[string]any
+
+	similarityPtr atomic.Pointer[similarity.Index]
+
+	alsPtr atomic.Pointer[als.Factors]
+
+	eventsMu     sync.Mutex
+	eventBuffers map[int]*userEventBuffer
+
+	flushMu       sync.Mutex
+	pendingEvents []Event
+}
+
+// movies returns the current catalog snapshot's movies. Safe to call
+// concurrently with enrichment swapping in a new snapshot.
+func (a *App) movies() []Movie {
+	c := a.catalog.Load()
+	if c == nil {
+		return nil
+	}
+	return c.movies
+}
+
+// movieByID looks up a single movie in the current catalog snapshot.
+func (a *App) movieByID(id int) (Movie, bool) {
+	c := a.catalog.Load()
+	if c == nil {
+		return Movie{}, false
+	}
+	m, ok := c.byID[id]
+	return m, ok
+}
+
+// similarityIndex returns the currently-active similarity index, or nil if
+// one hasn't been built yet.
+func (a *App) similarityIndex() *similarity.Index {
+	return a.similarityPtr.Load()
 }
 
+// alsFactors returns the current ALS factors, or nil if none were loaded
+// from disk at startup and no training run has completed since.
+func (a *App) alsFactors() *als.Factors {
+	return a.alsPtr.Load()
+}
+
+// alsFactorsFile holds the gob-encoded trained ALS factors.
+const alsFactorsFile = "als_factors.gob"
+
+// similarityIndexK is how many nearest neighbors are precomputed per movie.
+const similarityIndexK = 200
+
+// similarityCacheFile holds the gob-encoded similarity index, keyed by a
+// hash of the source CSV's mtime+size so a restart is instant unless the
+// data actually changed.
+const similarityCacheFile = "similarity_index.gob"
+
+// enrichStaleAfter is how long an enriched movie's TMDB data is considered
+// fresh before it's eligible to be re-fetched.
+const enrichStaleAfter = 7 * 24 * time.Hour
+
+// enrichQueueSize bounds the backlog of pending enrichment work;
+// enqueueStaleMovies enqueues every missing/stale movie at startup (and
+// drops any that don't fit), so this needs to comfortably exceed a typical
+// catalog size.
+const enrichQueueSize = 10000
+
+// enrichWorkerCount is the size of the background goroutine pool that
+// drains enrichQueue.
+const enrichWorkerCount = 4
+
 type ScoreWeights struct {
 	VoteAvg  float64
 	Pop      float64
 	CountLog float64
 	UserBias float64
 	MeanBias float64
+	// CFWeight blends in the ALS collaborative-filtering score (q_i . p_u)
+	// for users with trained factors; cold-start users are unaffected.
+	CFWeight float64
+	// RecentGenreBias weights the session-recency boost from
+	// UserFeatures.RecentGenres.
+	RecentGenreBias float64
 }
 
 type RankRequest struct {
 	UserID  *int `json:"user_id,omitempty"`
 	MovieID *int `json:"movie_id,omitempty"`
 	K       int  `json:"k"`
+	// Async, when set with UserID, enqueues a precompute-top-K job instead
+	// of ranking inline and returns its job ID in JobResponse.
+	Async bool `json:"async,omitempty"`
+	// Lambda trades relevance off against diversity in the MMR re-ranking
+	// pass; 1.0 is pure relevance, 0.0 is pure diversity. Defaults to 0.7.
+	// A pointer so an explicit 0 (pure diversity) isn't indistinguishable
+	// from an omitted field.
+	Lambda *float64 `json:"lambda,omitempty"`
+	// PoolSize is how many top-scored candidates the MMR pass re-ranks
+	// over before truncating to K. Defaults to 200.
+	PoolSize *int `json:"pool_size,omitempty"`
+}
+
+// JobResponse is returned by handleRank when Async is set.
+type JobResponse struct {
+	JobID  string `json:"job_id"`
+	Status string `json:"status"`
 }
 
 type RankResult struct {
@@ -91,12 +210,23 @@ func main() {
 		DataDir:    dataDir,
 		PosterBase: "https://image.tmdb.org/t/p/w342",
 		ScoreWeights: ScoreWeights{
-			VoteAvg:  0.15,
-			Pop:      0.02,
-			CountLog: 0.5,
-			UserBias: 1.0,
-			MeanBias: 1.0,
+			VoteAvg:         0.15,
+			Pop:             0.02,
+			CountLog:        0.5,
+			UserBias:        1.0,
+			MeanBias:        1.0,
+			CFWeight:        2.0,
+			RecentGenreBias: 0.5,
 		},
+		enrichQueue:  make(chan int, enrichQueueSize),
+		enrichedAt:   make(map[int]time.Time),
+		eventBuffers: make(map[int]*userEventBuffer),
+	}
+
+	if apiKey := getEnv("TMDB_API_KEY", ""); apiKey != "" {
+		app.TMDB = tmdb.NewClient(apiKey)
+	} else {
+		log.Printf("TMDB_API_KEY not set; live enrichment disabled")
 	}
 
 	log.Printf("Using data dir: %s", app.DataDir)
@@ -104,11 +234,32 @@ func main() {
 		log.Printf("Data load warning: %v", err)
 	}
 
+	if app.TMDB != nil {
+		app.loadEnrichmentCache()
+		app.startEnrichmentWorkers(enrichWorkerCount)
+		app.enqueueStaleMovies()
+	}
+
+	if err := app.initJobs(); err != nil {
+		log.Printf("job queue disabled: %v", err)
+	}
+
+	app.buildSimilarityIndex()
+	app.loadALSFactors()
+	app.loadRecentGenres()
+	app.startEventFlusher()
+
 	mux := http.NewServeMux()
 	mux.HandleFunc("/health", app.handleHealth)
 	mux.HandleFunc("/rank", app.handleRank)
 	mux.HandleFunc("/search", app.handleSearch)
 	mux.HandleFunc("/movie/", app.handleMovie)
+	mux.HandleFunc("/similar/", app.handleSimilar)
+	mux.HandleFunc("/admin/refresh", app.handleAdminRefresh)
+	mux.HandleFunc("/admin/train", app.handleAdminTrain)
+	mux.HandleFunc("/jobs", app.handleJobsCreate)
+	mux.HandleFunc("/jobs/", app.handleJobsGet)
+	mux.HandleFunc("/events", app.handleEvents)
 
 	addr := getEnv("PORT", "8080")
 	if !strings.HasPrefix(addr, ":") {
@@ -138,11 +289,11 @@ func (a *App) LoadData() error {
 		return fmt.Errorf("loaded empty data (movies=%d users=%d)", len(movies), len(users))
 	}
 
-	a.Movies = movies
-	a.MoviesByID = make(map[int]Movie, len(movies))
+	byID := make(map[int]Movie, len(movies))
 	for _, m := range movies {
-		a.MoviesByID[m.MovieID] = m
+		byID[m.MovieID] = m
 	}
+	a.catalog.Store(&catalogData{movies: movies, byID: byID})
 
 	a.UsersByID = make(map[int]UserFeatures, len(users))
 	for _, u := range users {
@@ -173,7 +324,7 @@ func (a *App) handleRank(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "POST required"})
 		return
 	}
-	if len(a.Movies) == 0 {
+	if len(a.movies()) == 0 {
 		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "no data loaded"})
 		return
 	}
@@ -186,17 +337,38 @@ func (a *App) handleRank(w http.ResponseWriter, r *http.Request) {
 	if req.K <= 0 {
 		req.K = 25
 	}
+	lambda := defaultMMRLambda
+	if req.Lambda != nil {
+		lambda = *req.Lambda
+		if lambda < 0 || lambda > 1 {
+			lambda = defaultMMRLambda
+		}
+	}
+	poolSize := defaultMMRPoolSize
+	if req.PoolSize != nil && *req.PoolSize > 0 {
+		poolSize = *req.PoolSize
+	}
 	if req.UserID == nil && req.MovieID == nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "user_id or movie_id required"})
 		return
 	}
 
+	if req.Async && req.UserID != nil && *req.UserID > 0 {
+		rec, err := a.enqueuePrecomputeTopK(*req.UserID, req.K)
+		if err != nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusAccepted, JobResponse{JobID: rec.ID, Status: string(rec.Status)})
+		return
+	}
+
 	var results []RankResult
 	var response RankResponse
 	response.LatencyMS = time.Since(start).Milliseconds()
 
 	if req.MovieID != nil && *req.MovieID > 0 {
-		seed, ok := a.MoviesByID[*req.MovieID]
+		seed, ok := a.movieByID(*req.MovieID)
 		if !ok {
 			writeJSON(w, http.StatusNotFound, map[string]string{"error": "movie not found"})
 			return
@@ -209,7 +381,7 @@ func (a *App) handleRank(w http.ResponseWriter, r *http.Request) {
 		if ok {
 			userPtr = &user
 		}
-		results = a.rankMovies(userPtr, req.K)
+		results = a.rankMovies(userPtr, req.K, lambda, poolSize)
 		response.UserID = *req.UserID
 	} else {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid user_id or movie_id"})
@@ -262,7 +434,7 @@ func (a *App) handleMovie(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	movie, ok := a.MoviesByID[id]
+	movie, ok := a.movieByID(id)
 	if !ok {
 		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
 		return
@@ -270,48 +442,151 @@ func (a *App) handleMovie(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, movie)
 }
 
-func (a *App) rankMovies(user *UserFeatures, k int) []RankResult {
-	results := make([]RankResult, 0, k)
+// defaultMMRLambda and defaultMMRPoolSize are used when RankRequest
+// doesn't override them.
+const (
+	defaultMMRLambda   = 0.7
+	defaultMMRPoolSize = 200
+)
 
-	type scored struct {
-		Movie Movie
-		Score float64
-	}
-	scoredMovies := make([]scored, 0, len(a.Movies))
-	for _, m := range a.Movies {
-		scoredMovies = append(scoredMovies, scored{Movie: m, Score: a.scoreMovie(m, user)})
+type scoredMovie struct {
+	Movie Movie
+	Score float64
+}
+
+// rankMovies scores every movie, takes the top poolSize by raw score, then
+// re-ranks that pool with Maximal Marginal Relevance so the final top-K
+// isn't dominated by near-duplicate popular titles.
+func (a *App) rankMovies(user *UserFeatures, k int, lambda float64, poolSize int) []RankResult {
+	dismissed := a.recentlyDismissed(user)
+
+	movies := a.movies()
+	scoredMovies := make([]scoredMovie, 0, len(movies))
+	for _, m := range movies {
+		if dismissed[m.MovieID] {
+			continue
+		}
+		scoredMovies = append(scoredMovies, scoredMovie{Movie: m, Score: a.scoreMovie(m, user)})
 	}
 
 	sort.Slice(scoredMovies, func(i, j int) bool {
 		return scoredMovies[i].Score > scoredMovies[j].Score
 	})
 
-	if k > len(scoredMovies) {
-		k = len(scoredMovies)
+	if poolSize > len(scoredMovies) {
+		poolSize = len(scoredMovies)
+	}
+	pool := scoredMovies[:poolSize]
+
+	if k > len(pool) {
+		k = len(pool)
+	}
+
+	return a.selectMMR(pool, user, k, lambda)
+}
+
+// selectMMR greedily selects k items from pool (already sorted by raw
+// score) maximizing Maximal Marginal Relevance:
+//
+//	lambda*score(m) - (1-lambda)*max_{s in selected} sim(m, s)
+//
+// trading relevance off against similarity to what's already been picked.
+func (a *App) selectMMR(pool []scoredMovie, user *UserFeatures, k int, lambda float64) []RankResult {
+	selected := make([]scoredMovie, 0, k)
+	remaining := append([]scoredMovie(nil), pool...)
+
+	for len(selected) < k && len(remaining) > 0 {
+		bestIdx := 0
+		bestMMR := math.Inf(-1)
+		for i, cand := range remaining {
+			maxSim := 0.0
+			for _, s := range selected {
+				if sim := mmrSimilarity(cand.Movie, s.Movie); sim > maxSim {
+					maxSim = sim
+				}
+			}
+			mmr := lambda*cand.Score - (1-lambda)*maxSim
+			if mmr > bestMMR {
+				bestMMR = mmr
+				bestIdx = i
+			}
+		}
+		selected = append(selected, remaining[bestIdx])
+		remaining = append(remaining[:bestIdx], remaining[bestIdx+1:]...)
+	}
+
+	results := make([]RankResult, 0, len(selected))
+	for i, s := range selected {
+		diversityBoost := i >= len(pool) || s.Movie.MovieID != pool[i].Movie.MovieID
+		results = append(results, RankResult{
+			MovieID:   s.Movie.MovieID,
+			Score:     s.Score,
+			Title:     s.Movie.Title,
+			PosterURL: joinPosterURL(a.PosterBase, s.Movie.TMDBPosterPath),
+			Reasons:   buildReasons(s.Movie, user, diversityBoost),
+		})
+	}
+	return results
+}
+
+// mmrSimilarity is the sim(m, s) term in the MMR objective: genre overlap
+// plus a rating-closeness term, so two movies with identical genres but
+// very different audience reception aren't treated as pure duplicates.
+func mmrSimilarity(a, b Movie) float64 {
+	sim := genreSimilarity(a, b)
+	ratingCloseness := 1 - math.Abs(a.RatingMean-b.RatingMean)/5.0
+	sim += 0.2 * ratingCloseness
+	if sim > 1 {
+		sim = 1
+	}
+	return sim
+}
+
+// rankMoviesByMovie serves item-item recommendations from the precomputed
+// similarity index when available, falling back to a linear scan when the
+// index hasn't been built yet or doesn't cover the seed movie.
+func (a *App) rankMoviesByMovie(seed Movie, k int) []RankResult {
+	if idx := a.similarityIndex(); idx != nil {
+		if neighbors, ok := idx.Neighbors[seed.MovieID]; ok {
+			return a.rankFromNeighbors(seed, neighbors, k)
+		}
 	}
+	return a.rankMoviesByMovieScan(seed, k)
+}
+
+func (a *App) rankFromNeighbors(seed Movie, neighbors []similarity.NeighborEntry, k int) []RankResult {
+	if k > len(neighbors) {
+		k = len(neighbors)
+	}
+	results := make([]RankResult, 0, k)
 	for i := 0; i < k; i++ {
-		m := scoredMovies[i].Movie
+		m, ok := a.movieByID(neighbors[i].MovieID)
+		if !ok {
+			continue
+		}
 		results = append(results, RankResult{
 			MovieID:   m.MovieID,
-			Score:     scoredMovies[i].Score,
+			Score:     neighbors[i].Score,
 			Title:     m.Title,
 			PosterURL: joinPosterURL(a.PosterBase, m.TMDBPosterPath),
-			Reasons:   buildReasons(m, user),
+			Reasons:   buildMovieReasons(seed, m),
 		})
 	}
-
 	return results
 }
 
-func (a *App) rankMoviesByMovie(seed Movie, k int) []RankResult {
+// rankMoviesByMovieScan is the O(N) fallback used before the similarity
+// index is built (or for a movie the index doesn't cover).
+func (a *App) rankMoviesByMovieScan(seed Movie, k int) []RankResult {
 	results := make([]RankResult, 0, k)
 
 	type scored struct {
 		Movie Movie
 		Score float64
 	}
-	scoredMovies := make([]scored, 0, len(a.Movies))
-	for _, m := range a.Movies {
+	movies := a.movies()
+	scoredMovies := make([]scored, 0, len(movies))
+	for _, m := range movies {
 		if m.MovieID == seed.MovieID {
 			continue
 		}
@@ -349,11 +624,31 @@ func (a *App) scoreMovie(m Movie, user *UserFeatures) float64 {
 	if user != nil {
 		score += a.ScoreWeights.UserBias * user.RatingMean
 		score -= a.ScoreWeights.MeanBias * math.Abs(m.RatingMean-user.RatingMean)
+
+		if factors := a.alsFactors(); factors != nil {
+			if cf, ok := factors.Score(user.UserID, m.MovieID); ok {
+				score += a.ScoreWeights.CFWeight * cf
+			}
+		}
+
+		if len(user.RecentGenres) > 0 {
+			score += a.ScoreWeights.RecentGenreBias * recentGenreOverlap(m, user.RecentGenres)
+		}
 	}
 	return score
 }
 
-func buildReasons(m Movie, user *UserFeatures) []string {
+// recentGenreOverlap sums the decayed recent-activity weight of every
+// genre m belongs to.
+func recentGenreOverlap(m Movie, recentGenres map[string]float64) float64 {
+	overlap := 0.0
+	for g := range parseGenres(preferGenres(m)) {
+		overlap += recentGenres[g]
+	}
+	return overlap
+}
+
+func buildReasons(m Movie, user *UserFeatures, diversityBoost bool) []string {
 	reasons := []string{}
 	if m.TMDBVoteAvg >= 7.5 {
 		reasons = append(reasons, "high_vote_avg")
@@ -361,9 +656,15 @@ func buildReasons(m Movie, user *UserFeatures) []string {
 	if m.RatingCount >= 1000 {
 		reasons = append(reasons, "popular_in_movielens")
 	}
+	if diversityBoost {
+		reasons = append(reasons, "diversity_boost")
+	}
 	if user != nil && math.Abs(m.RatingMean-user.RatingMean) <= 0.5 {
 		reasons = append(reasons, "matches_user_taste")
 	}
+	if user != nil && recentGenreOverlap(m, user.RecentGenres) > 0 {
+		reasons = append(reasons, "similar_to_recent_activity")
+	}
 	return reasons
 }
 
@@ -444,8 +745,9 @@ func (a *App) searchMovies(query string, limit int) []SearchResult {
 		Movie Movie
 		Score float64
 	}
-	scoredMovies := make([]scored, 0, len(a.Movies))
-	for _, m := range a.Movies {
+	movies := a.movies()
+	scoredMovies := make([]scored, 0, len(movies))
+	for _, m := range movies {
 		title := strings.ToLower(stripYear(m.Title))
 		score := 0.0
 		if strings.HasPrefix(title, q) {
@@ -593,6 +895,52 @@ func loadUsersCSV(path string) ([]UserFeatures, error) {
 	return users, nil
 }
 
+func loadRatingsCSV(path string) ([]als.Rating, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	idx := headerIndex(header)
+
+	required := []string{"userId", "movieId", "rating"}
+	for _, col := range required {
+		if _, ok := idx[col]; !ok {
+			return nil, fmt.Errorf("missing column %s in %s", col, path)
+		}
+	}
+
+	var ratings []als.Rating
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if err == csv.ErrFieldCount {
+				continue
+			}
+			return nil, err
+		}
+
+		ratings = append(ratings, als.Rating{
+			UserID:  parseInt(row, idx, "userId"),
+			MovieID: parseInt(row, idx, "movieId"),
+			Value:   parseFloat(row, idx, "rating"),
+		})
+	}
+
+	return ratings, nil
+}
+
 func headerIndex(header []string) map[string]int {
 	idx := make(map[string]int, len(header))
 	for i, col := range header {
@@ -0,0 +1,66 @@
+package main
+
+import "testing"
+
+func TestGenreSimilarityJaccard(t *testing.T) {
+	a := Movie{Genres: "Action|Sci-Fi"}
+	b := Movie{Genres: "Action|Comedy"}
+	c := Movie{Genres: "Romance|Drama"}
+
+	if got := genreSimilarity(a, b); got != 1.0/3.0 {
+		t.Fatalf("genreSimilarity(a, b) = %f, want 1/3", got)
+	}
+	if got := genreSimilarity(a, c); got != 0 {
+		t.Fatalf("genreSimilarity(a, c) = %f, want 0", got)
+	}
+	if got := genreSimilarity(a, a); got != 1 {
+		t.Fatalf("genreSimilarity(a, a) = %f, want 1", got)
+	}
+}
+
+func TestMMRSimilarityClampedToOne(t *testing.T) {
+	a := Movie{Genres: "Action", RatingMean: 4.0}
+	b := Movie{Genres: "Action", RatingMean: 4.0}
+	if got := mmrSimilarity(a, b); got != 1 {
+		t.Fatalf("mmrSimilarity(a, b) = %f, want 1 (clamped)", got)
+	}
+}
+
+func TestSelectMMRPureRelevanceIgnoresDiversity(t *testing.T) {
+	app := &App{}
+	pool := []scoredMovie{
+		{Movie: Movie{MovieID: 1, Genres: "Action", RatingMean: 4.0}, Score: 10},
+		{Movie: Movie{MovieID: 2, Genres: "Action", RatingMean: 4.0}, Score: 9.5},
+		{Movie: Movie{MovieID: 3, Genres: "Romance", RatingMean: 4.0}, Score: 8.0},
+	}
+
+	results := app.selectMMR(pool, nil, 2, 1.0)
+
+	if len(results) != 2 || results[0].MovieID != 1 || results[1].MovieID != 2 {
+		t.Fatalf("lambda=1 should select strictly by raw score, got %+v", results)
+	}
+}
+
+func TestSelectMMRPureDiversityPrefersDissimilarItem(t *testing.T) {
+	app := &App{}
+	pool := []scoredMovie{
+		{Movie: Movie{MovieID: 1, Genres: "Action", RatingMean: 4.0}, Score: 10},
+		{Movie: Movie{MovieID: 2, Genres: "Action", RatingMean: 4.0}, Score: 9.5},
+		{Movie: Movie{MovieID: 3, Genres: "Romance", RatingMean: 4.0}, Score: 8.0},
+	}
+
+	results := app.selectMMR(pool, nil, 2, 0.0)
+
+	if len(results) != 2 || results[0].MovieID != 1 || results[1].MovieID != 3 {
+		t.Fatalf("lambda=0 should prefer the genre-diverse movie 3 over the near-duplicate movie 2, got %+v", results)
+	}
+	found := false
+	for _, reason := range results[1].Reasons {
+		if reason == "diversity_boost" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected diversity_boost reason on the re-ranked pick, got %v", results[1].Reasons)
+	}
+}
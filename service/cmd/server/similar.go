@@ -0,0 +1,105 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/RohanSi4/MovieReccomenderSystem/service/similarity"
+)
+
+// buildSimilarityIndex loads a cached similarity index if the movie CSV
+// hasn't changed since it was built, otherwise (re)builds it and persists
+// the result.
+func (a *App) buildSimilarityIndex() {
+	cachePath := filepath.Join(a.DataDir, similarityCacheFile)
+	key, keyErr := a.similarityCacheKey()
+
+	if keyErr == nil {
+		if idx, ok := similarity.Load(cachePath, key); ok {
+			a.similarityPtr.Store(idx)
+			log.Printf("loaded similarity index from cache (%d movies, k=%d)", len(idx.Neighbors), idx.K)
+			return
+		}
+	}
+
+	movies := a.movies()
+	items := make([]similarity.Item, 0, len(movies))
+	for _, m := range movies {
+		items = append(items, similarity.Item{
+			MovieID:        m.MovieID,
+			Genres:         m.Genres,
+			TMDBGenres:     m.TMDBGenres,
+			RatingMean:     m.RatingMean,
+			RatingCount:    m.RatingCount,
+			TMDBVoteAvg:    m.TMDBVoteAvg,
+			TMDBPopularity: m.TMDBPopularity,
+		})
+	}
+
+	idx := similarity.Build(items, similarityIndexK)
+	a.similarityPtr.Store(idx)
+	log.Printf("built similarity index (%d movies, k=%d)", len(idx.Neighbors), idx.K)
+
+	if keyErr == nil {
+		if err := similarity.Save(cachePath, key, idx); err != nil {
+			log.Printf("similarity index: save cache: %v", err)
+		}
+	}
+}
+
+// similarityCacheKey identifies the data the index was built from, so a
+// stale cache from a previous dataset is never silently reused.
+func (a *App) similarityCacheKey() (string, error) {
+	info, err := os.Stat(filepath.Join(a.DataDir, "movie_features.csv"))
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d-%d", info.Size(), info.ModTime().UnixNano())))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// handleSimilar serves GET /similar/{id}?k=, a lightweight read-only path
+// for item-item recommendations that doesn't carry /rank's POST-body and
+// user-ranking overhead.
+func (a *App) handleSimilar(w http.ResponseWriter, r *http.Request) {
+	setCORS(w)
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "GET required"})
+		return
+	}
+
+	idStr := strings.TrimPrefix(r.URL.Path, "/similar/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid movie id"})
+		return
+	}
+	seed, ok := a.movieByID(id)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "movie not found"})
+		return
+	}
+
+	k := 25
+	if kParam := r.URL.Query().Get("k"); kParam != "" {
+		if parsed, err := strconv.Atoi(kParam); err == nil && parsed > 0 {
+			k = parsed
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"movie_id": id,
+		"results":  a.rankMoviesByMovie(seed, k),
+	})
+}
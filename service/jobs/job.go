@@ -0,0 +1,58 @@
+// Package jobs implements a small persistent job queue for work that's too
+// slow to do inline on the request path: TMDB enrichment, similarity
+// precomputation, poster downloads, and model retraining.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Status is the lifecycle state of an enqueued job.
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusRunning Status = "running"
+	StatusDone    Status = "done"
+	StatusFailed  Status = "failed"
+)
+
+// Kind identifies what a job does; it's also the lookup key into the
+// Queue's factory registry.
+type Kind string
+
+const (
+	KindEnrichMovie          Kind = "enrich_movie"
+	KindPrecomputeSimilarity Kind = "precompute_similarity"
+	KindPosterDownload       Kind = "poster_download"
+	KindRetrainWeights       Kind = "retrain_weights"
+	KindPrecomputeTopK       Kind = "precompute_topk"
+)
+
+// Job is a unit of work the queue can run. Run may be invoked more than
+// once for the same logical job if a previous attempt failed and
+// MaxRetries has not been exhausted, so implementations should be safe to
+// retry.
+type Job interface {
+	Kind() Kind
+	Run(ctx context.Context) error
+}
+
+// Factory reconstructs a runnable Job from a persisted payload. id is the
+// job's Record ID, which some jobs use to publish results keyed by job.
+type Factory func(id string, payload json.RawMessage) (Job, error)
+
+// Record is the persisted state of an enqueued Job.
+type Record struct {
+	ID         string          `json:"id"`
+	Kind       Kind            `json:"kind"`
+	Payload    json.RawMessage `json:"payload"`
+	Status     Status          `json:"status"`
+	Attempts   int             `json:"attempts"`
+	MaxRetries int             `json:"max_retries"`
+	Error      string          `json:"error,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
@@ -0,0 +1,110 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+)
+
+// queueBacklog bounds how many job IDs can be buffered waiting for a free
+// worker before Enqueue falls back to relying on store-backed resume.
+const queueBacklog = 1000
+
+// Queue dispatches enqueued jobs to a pool of workers, persisting state via
+// a Store so job status (and retries) survive a restart.
+type Queue struct {
+	store      *Store
+	factories  map[Kind]Factory
+	work       chan string
+	maxRetries int
+}
+
+// NewQueue creates a Queue backed by store with the given job factories
+// registered by kind. maxRetries applies to every job kind.
+func NewQueue(store *Store, factories map[Kind]Factory, maxRetries int) *Queue {
+	return &Queue{
+		store:      store,
+		factories:  factories,
+		work:       make(chan string, queueBacklog),
+		maxRetries: maxRetries,
+	}
+}
+
+// Enqueue persists a new pending job for kind/payload and schedules it to
+// run on a worker, returning its Record (notably its ID).
+func (q *Queue) Enqueue(kind Kind, payload any) (*Record, error) {
+	if _, ok := q.factories[kind]; !ok {
+		return nil, fmt.Errorf("jobs: no factory registered for kind %q", kind)
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	rec := q.store.Create(kind, raw, q.maxRetries)
+	q.schedule(rec.ID)
+	return rec, nil
+}
+
+// Get returns the current state of a job by ID.
+func (q *Queue) Get(id string) (*Record, bool) {
+	return q.store.Get(id)
+}
+
+// StartWorkers resumes any jobs left pending/running by a previous process
+// and launches n goroutines to drain the work queue.
+func (q *Queue) StartWorkers(ctx context.Context, n int) {
+	for _, rec := range q.store.PendingAndRunning() {
+		q.schedule(rec.ID)
+	}
+	for i := 0; i < n; i++ {
+		go q.worker(ctx)
+	}
+}
+
+func (q *Queue) schedule(id string) {
+	select {
+	case q.work <- id:
+	default:
+		log.Printf("jobs: work channel full, job %s stays pending until the queue is restarted", id)
+	}
+}
+
+func (q *Queue) worker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-q.work:
+			q.run(ctx, id)
+		}
+	}
+}
+
+func (q *Queue) run(ctx context.Context, id string) {
+	rec, ok := q.store.Get(id)
+	if !ok {
+		return
+	}
+	factory, ok := q.factories[rec.Kind]
+	if !ok {
+		q.store.Fail(id, fmt.Sprintf("no factory registered for kind %q", rec.Kind))
+		return
+	}
+	job, err := factory(id, rec.Payload)
+	if err != nil {
+		q.store.Fail(id, err.Error())
+		return
+	}
+
+	q.store.MarkRunning(id)
+	if runErr := job.Run(ctx); runErr == nil {
+		q.store.MarkDone(id)
+		return
+	} else if rec, _ = q.store.Get(id); rec.Attempts < rec.MaxRetries {
+		q.store.MarkPending(id, runErr.Error())
+		q.schedule(id)
+	} else {
+		q.store.Fail(id, runErr.Error())
+	}
+}
@@ -0,0 +1,101 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingJob fails its first failUntil invocations, then succeeds.
+type countingJob struct {
+	kind      Kind
+	failUntil int32
+	attempts  *int32
+}
+
+func (j *countingJob) Kind() Kind { return j.kind }
+
+func (j *countingJob) Run(ctx context.Context) error {
+	if atomic.AddInt32(j.attempts, 1) <= j.failUntil {
+		return errors.New("boom")
+	}
+	return nil
+}
+
+func newTestQueue(t *testing.T, maxRetries int, factory Factory) *Queue {
+	t.Helper()
+	store, err := NewStore(filepath.Join(t.TempDir(), "jobs.json"))
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	return NewQueue(store, map[Kind]Factory{KindEnrichMovie: factory}, maxRetries)
+}
+
+func waitForStatus(t *testing.T, q *Queue, id string, want Status) *Record {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if rec, ok := q.Get(id); ok && rec.Status == want {
+			return rec
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("job %s never reached status %q", id, want)
+	return nil
+}
+
+func TestQueueRetriesThenSucceeds(t *testing.T) {
+	var attempts int32
+	q := newTestQueue(t, 3, func(id string, payload json.RawMessage) (Job, error) {
+		return &countingJob{kind: KindEnrichMovie, failUntil: 2, attempts: &attempts}, nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	q.StartWorkers(ctx, 1)
+
+	rec, err := q.Enqueue(KindEnrichMovie, map[string]string{})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	done := waitForStatus(t, q, rec.ID, StatusDone)
+	if done.Attempts != 3 {
+		t.Fatalf("Attempts = %d, want 3", done.Attempts)
+	}
+}
+
+func TestQueueFailsAfterMaxRetries(t *testing.T) {
+	var attempts int32
+	q := newTestQueue(t, 2, func(id string, payload json.RawMessage) (Job, error) {
+		return &countingJob{kind: KindEnrichMovie, failUntil: 100, attempts: &attempts}, nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	q.StartWorkers(ctx, 1)
+
+	rec, err := q.Enqueue(KindEnrichMovie, map[string]string{})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	failed := waitForStatus(t, q, rec.ID, StatusFailed)
+	if failed.Attempts != 2 {
+		t.Fatalf("Attempts = %d, want 2", failed.Attempts)
+	}
+	if failed.Error == "" {
+		t.Fatal("expected Error to record the last failure")
+	}
+}
+
+func TestQueueEnqueueUnregisteredKind(t *testing.T) {
+	q := newTestQueue(t, 1, func(id string, payload json.RawMessage) (Job, error) {
+		return nil, errors.New("should not be called")
+	})
+	if _, err := q.Enqueue(Kind("unregistered"), map[string]string{}); err == nil {
+		t.Fatal("expected an error enqueuing an unregistered kind")
+	}
+}
@@ -0,0 +1,145 @@
+package jobs
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Store persists job Records to a single JSON file, keyed by ID. It plays
+// the role a SQLite or BoltDB table would in a larger deployment; the only
+// contract that matters here is that status and retry counts survive a
+// process restart, so a flat file is enough for this service's scale.
+type Store struct {
+	mu   sync.Mutex
+	path string
+	recs map[string]*Record
+}
+
+// NewStore loads (or initializes, if path doesn't exist yet) a Store.
+func NewStore(path string) (*Store, error) {
+	s := &Store{path: path, recs: make(map[string]*Record)}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	if len(data) == 0 {
+		return s, nil
+	}
+	if err := json.Unmarshal(data, &s.recs); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Create persists a new pending Record for kind/payload and returns it.
+func (s *Store) Create(kind Kind, payload json.RawMessage, maxRetries int) *Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	rec := &Record{
+		ID:         newID(),
+		Kind:       kind,
+		Payload:    payload,
+		Status:     StatusPending,
+		MaxRetries: maxRetries,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	s.recs[rec.ID] = rec
+	s.saveLocked()
+	return rec
+}
+
+// Get returns a copy of the Record for id.
+func (s *Store) Get(id string) (*Record, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec, ok := s.recs[id]
+	if !ok {
+		return nil, false
+	}
+	cp := *rec
+	return &cp, true
+}
+
+// PendingAndRunning returns jobs left unfinished by a previous process, so
+// the queue can resume them after a restart.
+func (s *Store) PendingAndRunning() []*Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []*Record
+	for _, rec := range s.recs {
+		if rec.Status == StatusPending || rec.Status == StatusRunning {
+			cp := *rec
+			out = append(out, &cp)
+		}
+	}
+	return out
+}
+
+func (s *Store) MarkRunning(id string) {
+	s.update(id, func(r *Record) {
+		r.Status = StatusRunning
+		r.Attempts++
+	})
+}
+
+func (s *Store) MarkDone(id string) {
+	s.update(id, func(r *Record) {
+		r.Status = StatusDone
+		r.Error = ""
+	})
+}
+
+// MarkPending requeues a job that failed but still has retries left,
+// recording the error from the most recent attempt.
+func (s *Store) MarkPending(id, lastErr string) {
+	s.update(id, func(r *Record) {
+		r.Status = StatusPending
+		r.Error = lastErr
+	})
+}
+
+func (s *Store) Fail(id, lastErr string) {
+	s.update(id, func(r *Record) {
+		r.Status = StatusFailed
+		r.Error = lastErr
+	})
+}
+
+func (s *Store) update(id string, mutate func(*Record)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec, ok := s.recs[id]
+	if !ok {
+		return
+	}
+	mutate(rec)
+	rec.UpdatedAt = time.Now()
+	s.saveLocked()
+}
+
+func (s *Store) saveLocked() {
+	data, err := json.MarshalIndent(s.recs, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(s.path, data, 0o644)
+}
+
+// newID generates a random UUIDv4 without pulling in an external package.
+func newID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
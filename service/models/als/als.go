@@ -0,0 +1,59 @@
+// Package als implements implicit-feedback Alternating Least Squares
+// (Hu, Koren & Volinsky, 2008) for collaborative-filtering movie
+// recommendations, learning user and item latent factors from an
+// (userId, movieId, rating) interaction log.
+package als
+
+// Rating is one observed interaction: userID interacted with movieID with
+// strength value (e.g. a MovieLens 1-5 star rating).
+type Rating struct {
+	UserID  int
+	MovieID int
+	Value   float64
+}
+
+// Config controls an ALS training run.
+type Config struct {
+	// D is the number of latent factors per user/item.
+	D int
+	// Lambda is the ridge regularization strength.
+	Lambda float64
+	// Alpha scales rating value into confidence: c_ui = 1 + Alpha*r_ui.
+	Alpha float64
+	// Iterations is how many alternating user/item passes to run.
+	Iterations int
+}
+
+// DefaultConfig is what this service trains with.
+func DefaultConfig() Config {
+	return Config{D: 32, Lambda: 0.1, Alpha: 40, Iterations: 15}
+}
+
+// Factors holds trained user (P) and item (Q) latent vectors, addressed by
+// MovieLens userId/movieId via UserIndex/ItemIndex.
+type Factors struct {
+	D         int
+	UserIndex map[int]int
+	ItemIndex map[int]int
+	P         [][]float64
+	Q         [][]float64
+}
+
+// Score returns the predicted affinity q_i . p_u for a known user/movie
+// pair. ok is false if either ID wasn't present in the training data.
+func (f *Factors) Score(userID, movieID int) (score float64, ok bool) {
+	ui, ok1 := f.UserIndex[userID]
+	ii, ok2 := f.ItemIndex[movieID]
+	if !ok1 || !ok2 {
+		return 0, false
+	}
+	return dot(f.P[ui], f.Q[ii]), true
+}
+
+func dot(a, b []float64) float64 {
+	sum := 0.0
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
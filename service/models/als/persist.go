@@ -0,0 +1,31 @@
+package als
+
+import (
+	"encoding/gob"
+	"os"
+)
+
+// Save gob-encodes f to path.
+func Save(path string, f *Factors) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return gob.NewEncoder(file).Encode(f)
+}
+
+// Load reads Factors previously written by Save.
+func Load(path string) (*Factors, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var f Factors
+	if err := gob.NewDecoder(file).Decode(&f); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
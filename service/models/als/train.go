@@ -0,0 +1,187 @@
+package als
+
+import "math/rand"
+
+// entry is one observed interaction from the perspective of the other
+// side's index: e.g. for a user, which item index was rated and at what
+// confidence.
+type entry struct {
+	idx        int
+	confidence float64
+}
+
+// Train learns user and item factors from ratings via implicit-feedback
+// ALS: confidence c_ui = 1 + Alpha*r_ui, and preference p_ui = 1 for every
+// observed (u,i) pair (0 otherwise, handled implicitly by only summing
+// over observed interactions in solveFactors).
+func Train(ratings []Rating, cfg Config) *Factors {
+	userIndex, itemIndex := buildIndexes(ratings)
+	byUser, byItem := groupByUserAndItem(ratings, userIndex, itemIndex, cfg.Alpha)
+
+	f := &Factors{
+		D:         cfg.D,
+		UserIndex: userIndex,
+		ItemIndex: itemIndex,
+		P:         randomFactors(len(userIndex), cfg.D),
+		Q:         randomFactors(len(itemIndex), cfg.D),
+	}
+
+	for iter := 0; iter < cfg.Iterations; iter++ {
+		solveFactors(f.P, f.Q, byUser, cfg)
+		solveFactors(f.Q, f.P, byItem, cfg)
+	}
+	return f
+}
+
+func buildIndexes(ratings []Rating) (userIndex, itemIndex map[int]int) {
+	userIndex = make(map[int]int)
+	itemIndex = make(map[int]int)
+	for _, r := range ratings {
+		if _, ok := userIndex[r.UserID]; !ok {
+			userIndex[r.UserID] = len(userIndex)
+		}
+		if _, ok := itemIndex[r.MovieID]; !ok {
+			itemIndex[r.MovieID] = len(itemIndex)
+		}
+	}
+	return userIndex, itemIndex
+}
+
+func groupByUserAndItem(ratings []Rating, userIndex, itemIndex map[int]int, alpha float64) (byUser, byItem map[int][]entry) {
+	byUser = make(map[int][]entry)
+	byItem = make(map[int][]entry)
+	for _, r := range ratings {
+		u := userIndex[r.UserID]
+		i := itemIndex[r.MovieID]
+		c := 1 + alpha*r.Value
+		byUser[u] = append(byUser[u], entry{idx: i, confidence: c})
+		byItem[i] = append(byItem[i], entry{idx: u, confidence: c})
+	}
+	return byUser, byItem
+}
+
+// solveFactors updates target[idx] for every idx with observed
+// interactions in groups, holding other fixed. This is the standard
+// implicit-ALS closed-form ridge update:
+//
+//	p_u = (Q^T Q + sum_i (c_ui-1) q_i q_i^T + lambda*I)^-1 * sum_i c_ui*q_i
+//
+// where the sums range only over items i the user actually interacted
+// with (Q^T Q is precomputed once per call since it's shared across all
+// users/items being solved).
+func solveFactors(target, other [][]float64, groups map[int][]entry, cfg Config) {
+	d := cfg.D
+	base := gram(other, d)
+
+	for idx, obs := range groups {
+		a := cloneMatrix(base)
+		addDiag(a, cfg.Lambda)
+		b := make([]float64, d)
+
+		for _, e := range obs {
+			v := other[e.idx]
+			for r := 0; r < d; r++ {
+				b[r] += e.confidence * v[r]
+				for c := 0; c < d; c++ {
+					a[r][c] += (e.confidence - 1) * v[r] * v[c]
+				}
+			}
+		}
+		target[idx] = solveLinear(a, b)
+	}
+}
+
+func gram(vectors [][]float64, d int) [][]float64 {
+	g := make([][]float64, d)
+	for i := range g {
+		g[i] = make([]float64, d)
+	}
+	for _, v := range vectors {
+		for i := 0; i < d; i++ {
+			for j := 0; j < d; j++ {
+				g[i][j] += v[i] * v[j]
+			}
+		}
+	}
+	return g
+}
+
+func cloneMatrix(m [][]float64) [][]float64 {
+	out := make([][]float64, len(m))
+	for i, row := range m {
+		out[i] = append([]float64(nil), row...)
+	}
+	return out
+}
+
+func addDiag(m [][]float64, lambda float64) {
+	for i := range m {
+		m[i][i] += lambda
+	}
+}
+
+func randomFactors(n, d int) [][]float64 {
+	// Seeded deterministically so training runs (and the gob files they
+	// produce) are reproducible given the same ratings input.
+	rng := rand.New(rand.NewSource(42))
+	out := make([][]float64, n)
+	for i := range out {
+		row := make([]float64, d)
+		for j := range row {
+			row[j] = (rng.Float64() - 0.5) * 0.1
+		}
+		out[i] = row
+	}
+	return out
+}
+
+// solveLinear solves a*x = b for x via Gaussian elimination with partial
+// pivoting. a is small (d is typically ~32), so this is fast enough to
+// call per user/item per iteration without an external linear algebra
+// dependency.
+func solveLinear(a [][]float64, b []float64) []float64 {
+	n := len(b)
+	aug := make([][]float64, n)
+	for i := range aug {
+		aug[i] = append(append([]float64(nil), a[i]...), b[i])
+	}
+
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if abs(aug[row][col]) > abs(aug[pivot][col]) {
+				pivot = row
+			}
+		}
+		aug[col], aug[pivot] = aug[pivot], aug[col]
+		if aug[col][col] == 0 {
+			continue
+		}
+		for row := col + 1; row < n; row++ {
+			factor := aug[row][col] / aug[col][col]
+			for c := col; c <= n; c++ {
+				aug[row][c] -= factor * aug[col][c]
+			}
+		}
+	}
+
+	x := make([]float64, n)
+	for row := n - 1; row >= 0; row-- {
+		sum := aug[row][n]
+		for col := row + 1; col < n; col++ {
+			sum -= aug[row][col] * x[col]
+		}
+		if aug[row][row] == 0 {
+			continue
+		}
+		x[row] = sum / aug[row][row]
+	}
+	return x
+}
+
+func abs(x float64) float64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,70 @@
+package als
+
+import (
+	"math"
+	"testing"
+)
+
+// TestTrainRanksLikedItemAboveUnseenItem checks the core property implicit
+// ALS is supposed to deliver: after training on two disjoint clusters of
+// users and items (cluster A never overlaps with cluster B), a cluster-A
+// user's predicted affinity for a cluster-A item should exceed their
+// affinity for a cluster-B item they share no co-occurring users with.
+func TestTrainRanksLikedItemAboveUnseenItem(t *testing.T) {
+	var ratings []Rating
+	for _, u := range []int{1, 2, 3} {
+		for _, m := range []int{101, 102, 103} {
+			ratings = append(ratings, Rating{UserID: u, MovieID: m, Value: 5})
+		}
+	}
+	for _, u := range []int{4, 5, 6} {
+		for _, m := range []int{201, 202, 203} {
+			ratings = append(ratings, Rating{UserID: u, MovieID: m, Value: 5})
+		}
+	}
+
+	factors := Train(ratings, Config{D: 4, Lambda: 0.1, Alpha: 40, Iterations: 15})
+
+	inCluster, ok := factors.Score(1, 101)
+	if !ok {
+		t.Fatal("expected a score for a user/item pair seen during training")
+	}
+	crossCluster, ok := factors.Score(1, 201)
+	if !ok {
+		t.Fatal("expected a score even for a pair from different clusters (both were seen during training)")
+	}
+	if _, ok := factors.Score(1, 999); ok {
+		t.Fatal("expected ok=false for an item never present in the ratings")
+	}
+
+	if inCluster <= crossCluster {
+		t.Fatalf("in-cluster score (%f) should exceed cross-cluster score (%f)", inCluster, crossCluster)
+	}
+}
+
+func TestScoreUnknownUserOrItem(t *testing.T) {
+	factors := Train([]Rating{{UserID: 1, MovieID: 10, Value: 5}}, DefaultConfig())
+
+	if _, ok := factors.Score(999, 10); ok {
+		t.Fatal("expected ok=false for an unknown user")
+	}
+	if _, ok := factors.Score(1, 999); ok {
+		t.Fatal("expected ok=false for an unknown item")
+	}
+}
+
+func TestSolveLinearSolvesKnownSystem(t *testing.T) {
+	// [2 1][x]   [5]
+	// [1 3][y] = [10]
+	a := [][]float64{{2, 1}, {1, 3}}
+	b := []float64{5, 10}
+
+	x := solveLinear(a, b)
+
+	want := []float64{1, 3}
+	for i := range want {
+		if math.Abs(x[i]-want[i]) > 1e-9 {
+			t.Fatalf("solveLinear(%v, %v) = %v, want %v", a, b, x, want)
+		}
+	}
+}
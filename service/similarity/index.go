@@ -0,0 +1,108 @@
+// Package similarity builds a precomputed item-item nearest-neighbor
+// index over movie feature vectors, so item-based recommendations are a
+// map lookup instead of an O(N) scan of the whole catalog per request.
+package similarity
+
+import (
+	"encoding/gob"
+	"os"
+	"sort"
+)
+
+// Item is the subset of a movie's fields needed to build its feature
+// vector. Callers (the main package's Movie type) convert into this shape
+// so this package stays independent of the service's storage layer.
+type Item struct {
+	MovieID        int
+	Genres         string
+	TMDBGenres     string
+	RatingMean     float64
+	RatingCount    int
+	TMDBVoteAvg    float64
+	TMDBPopularity float64
+}
+
+// NeighborEntry is one entry of a movie's precomputed neighbor list.
+type NeighborEntry struct {
+	MovieID int
+	Score   float64
+}
+
+// Index maps a movie ID to its top-K most-similar movies by cosine
+// similarity over normalized feature vectors.
+type Index struct {
+	K         int
+	Neighbors map[int][]NeighborEntry
+}
+
+// Build computes per-movie feature vectors from items and, for each
+// movie, the top-K most-similar other movies by cosine similarity.
+func Build(items []Item, k int) *Index {
+	vocab := genreVocab(items)
+	ratingMean, ratingStd := ratingMeanStats(items)
+
+	ids := make([]int, 0, len(items))
+	vectors := make(map[int][]float64, len(items))
+	for _, it := range items {
+		ids = append(ids, it.MovieID)
+		vectors[it.MovieID] = featureVector(it, vocab, ratingMean, ratingStd)
+	}
+
+	neighbors := make(map[int][]NeighborEntry, len(items))
+	for _, id := range ids {
+		v := vectors[id]
+		entries := make([]NeighborEntry, 0, len(ids)-1)
+		for _, other := range ids {
+			if other == id {
+				continue
+			}
+			entries = append(entries, NeighborEntry{MovieID: other, Score: dot(v, vectors[other])})
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Score > entries[j].Score })
+		if len(entries) > k {
+			entries = entries[:k]
+		}
+		neighbors[id] = entries
+	}
+
+	return &Index{K: k, Neighbors: neighbors}
+}
+
+// cacheEnvelope pairs a persisted Index with the key it was built from, so
+// Load can reject a cache built from different data. The key is compared
+// after the whole envelope, index included, has been decoded.
+type cacheEnvelope struct {
+	Key   string
+	Index Index
+}
+
+// Save gob-encodes idx to path alongside key (typically a hash of the
+// source CSV's mtime+size).
+func Save(path, key string, idx *Index) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return gob.NewEncoder(f).Encode(cacheEnvelope{Key: key, Index: *idx})
+}
+
+// Load reads an Index previously written by Save, returning ok=false if
+// the file doesn't exist, is unreadable, or was built for a different key.
+func Load(path, key string) (idx *Index, ok bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+
+	var env cacheEnvelope
+	if err := gob.NewDecoder(f).Decode(&env); err != nil {
+		return nil, false
+	}
+	if env.Key != key {
+		return nil, false
+	}
+	loaded := env.Index
+	return &loaded, true
+}
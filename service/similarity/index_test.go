@@ -0,0 +1,79 @@
+package similarity
+
+import "testing"
+
+func TestBuildRanksGenreMatchesAboveMismatches(t *testing.T) {
+	items := []Item{
+		{MovieID: 1, Genres: "Action|Sci-Fi", RatingMean: 4.0, RatingCount: 500, TMDBVoteAvg: 7.5, TMDBPopularity: 50},
+		{MovieID: 2, Genres: "Action|Sci-Fi", RatingMean: 4.1, RatingCount: 400, TMDBVoteAvg: 7.2, TMDBPopularity: 45},
+		{MovieID: 3, Genres: "Romance|Drama", RatingMean: 3.9, RatingCount: 300, TMDBVoteAvg: 6.8, TMDBPopularity: 20},
+	}
+
+	idx := Build(items, 2)
+
+	neighbors, ok := idx.Neighbors[1]
+	if !ok || len(neighbors) == 0 {
+		t.Fatalf("expected neighbors for movie 1, got %v", neighbors)
+	}
+	if neighbors[0].MovieID != 2 {
+		t.Fatalf("top neighbor of movie 1 = %d, want 2 (same genres)", neighbors[0].MovieID)
+	}
+	if neighbors[0].Score <= 0 {
+		t.Fatalf("expected a positive cosine similarity, got %f", neighbors[0].Score)
+	}
+}
+
+func TestBuildCapsNeighborsAtK(t *testing.T) {
+	items := make([]Item, 0, 10)
+	for i := 1; i <= 10; i++ {
+		items = append(items, Item{MovieID: i, Genres: "Drama", RatingMean: 3.5, RatingCount: 100})
+	}
+
+	idx := Build(items, 3)
+
+	for id, neighbors := range idx.Neighbors {
+		if len(neighbors) > 3 {
+			t.Fatalf("movie %d has %d neighbors, want at most 3", id, len(neighbors))
+		}
+	}
+}
+
+func TestBuildExcludesSelfFromNeighbors(t *testing.T) {
+	items := []Item{
+		{MovieID: 1, Genres: "Comedy", RatingMean: 4.0, RatingCount: 100},
+		{MovieID: 2, Genres: "Comedy", RatingMean: 4.0, RatingCount: 100},
+	}
+
+	idx := Build(items, 5)
+
+	for _, n := range idx.Neighbors[1] {
+		if n.MovieID == 1 {
+			t.Fatal("movie 1's neighbor list should not include itself")
+		}
+	}
+}
+
+func TestSaveLoadRoundTripsAndDetectsStaleKey(t *testing.T) {
+	items := []Item{
+		{MovieID: 1, Genres: "Action", RatingMean: 4.0, RatingCount: 100},
+		{MovieID: 2, Genres: "Action", RatingMean: 3.8, RatingCount: 80},
+	}
+	idx := Build(items, 5)
+
+	path := t.TempDir() + "/index.gob"
+	if err := Save(path, "key-v1", idx); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, ok := Load(path, "key-v1")
+	if !ok {
+		t.Fatal("Load with matching key should succeed")
+	}
+	if len(loaded.Neighbors) != len(idx.Neighbors) {
+		t.Fatalf("loaded %d neighbor lists, want %d", len(loaded.Neighbors), len(idx.Neighbors))
+	}
+
+	if _, ok := Load(path, "key-v2"); ok {
+		t.Fatal("Load with a mismatched key should report ok=false")
+	}
+}
@@ -0,0 +1,110 @@
+package similarity
+
+import (
+	"math"
+	"strings"
+)
+
+// unionGenres returns the lowercased, deduplicated set of genre tokens
+// present in either it.Genres or it.TMDBGenres.
+func unionGenres(it Item) []string {
+	seen := make(map[string]bool)
+	for _, raw := range []string{it.Genres, it.TMDBGenres} {
+		for _, token := range strings.Split(raw, "|") {
+			g := strings.ToLower(strings.TrimSpace(token))
+			if g != "" {
+				seen[g] = true
+			}
+		}
+	}
+	out := make([]string, 0, len(seen))
+	for g := range seen {
+		out = append(out, g)
+	}
+	return out
+}
+
+// genreVocab assigns a distinct vector index to every genre token observed
+// across items. Indices follow map iteration order, so they can differ
+// from one call to the next.
+func genreVocab(items []Item) map[string]int {
+	seen := make(map[string]bool)
+	for _, it := range items {
+		for _, g := range unionGenres(it) {
+			seen[g] = true
+		}
+	}
+	vocab := make(map[string]int, len(seen))
+	for g := range seen {
+		vocab[g] = len(vocab)
+	}
+	return vocab
+}
+
+// ratingMeanStats returns the mean and population standard deviation of
+// RatingMean across items, used to z-score that feature.
+func ratingMeanStats(items []Item) (mean, std float64) {
+	if len(items) == 0 {
+		return 0, 0
+	}
+	sum := 0.0
+	for _, it := range items {
+		sum += it.RatingMean
+	}
+	mean = sum / float64(len(items))
+
+	variance := 0.0
+	for _, it := range items {
+		d := it.RatingMean - mean
+		variance += d * d
+	}
+	variance /= float64(len(items))
+	return mean, math.Sqrt(variance)
+}
+
+// featureVector builds the L2-normalized feature vector for it: one-hot
+// genres over vocab, z-scored RatingMean, log1p(RatingCount), raw
+// TMDBVoteAvg, and log1p(TMDBPopularity).
+func featureVector(it Item, vocab map[string]int, ratingMean, ratingStd float64) []float64 {
+	v := make([]float64, len(vocab)+4)
+	for _, g := range unionGenres(it) {
+		if idx, ok := vocab[g]; ok {
+			v[idx] = 1
+		}
+	}
+
+	z := 0.0
+	if ratingStd > 0 {
+		z = (it.RatingMean - ratingMean) / ratingStd
+	}
+	v[len(vocab)+0] = z
+	v[len(vocab)+1] = math.Log1p(float64(it.RatingCount))
+	v[len(vocab)+2] = it.TMDBVoteAvg
+	v[len(vocab)+3] = math.Log1p(it.TMDBPopularity)
+
+	return l2Normalize(v)
+}
+
+func l2Normalize(v []float64) []float64 {
+	sumSq := 0.0
+	for _, x := range v {
+		sumSq += x * x
+	}
+	norm := math.Sqrt(sumSq)
+	if norm == 0 {
+		return v
+	}
+	out := make([]float64, len(v))
+	for i, x := range v {
+		out[i] = x / norm
+	}
+	return out
+}
+
+func dot(a, b []float64) float64 {
+	sum := 0.0
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
@@ -0,0 +1,113 @@
+// Package tmdb is a small, rate-limited client for the subset of the TMDB
+// (The Movie Database) REST API used to enrich the MovieLens catalog with
+// live metadata: movie details, search, and similar-movie lookups.
+package tmdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultBaseURL = "https://api.themoviedb.org/3"
+
+	// TMDB's documented rate limit is roughly 40 requests per 10 seconds
+	// per API key.
+	rateLimitRequests = 40
+	rateLimitWindow   = 10 * time.Second
+)
+
+// Genre mirrors a single entry of TMDB's genre objects.
+type Genre struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// MovieDetails mirrors the subset of TMDB's /movie/{id} response (and the
+// per-item shape of /search/movie and /movie/{id}/similar) that the
+// recommender needs.
+type MovieDetails struct {
+	ID          int     `json:"id"`
+	Title       string  `json:"title"`
+	VoteAverage float64 `json:"vote_average"`
+	Popularity  float64 `json:"popularity"`
+	PosterPath  string  `json:"poster_path"`
+	Overview    string  `json:"overview"`
+	ReleaseDate string  `json:"release_date"`
+	Genres      []Genre `json:"genres"`
+}
+
+type pagedResults struct {
+	Results []MovieDetails `json:"results"`
+}
+
+// Client is a rate-limited TMDB REST client. A single Client (and its
+// shared limiter) should be reused across goroutines rather than created
+// per-request.
+type Client struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+	limiter    *TokenBucket
+}
+
+// NewClient builds a Client that authenticates requests with apiKey.
+func NewClient(apiKey string) *Client {
+	return &Client{
+		apiKey:     apiKey,
+		baseURL:    defaultBaseURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		limiter:    NewTokenBucket(rateLimitRequests, rateLimitWindow),
+	}
+}
+
+func (c *Client) get(ctx context.Context, path string, query url.Values, out any) error {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("api_key", c.apiKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("tmdb: %s returned status %d", path, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Movie fetches /movie/{id}.
+func (c *Client) Movie(ctx context.Context, id int) (MovieDetails, error) {
+	var out MovieDetails
+	err := c.get(ctx, "/movie/"+strconv.Itoa(id), nil, &out)
+	return out, err
+}
+
+// SearchMovie fetches /search/movie?query=...
+func (c *Client) SearchMovie(ctx context.Context, query string) ([]MovieDetails, error) {
+	var out pagedResults
+	err := c.get(ctx, "/search/movie", url.Values{"query": {query}}, &out)
+	return out.Results, err
+}
+
+// SimilarMovies fetches /movie/{id}/similar.
+func (c *Client) SimilarMovies(ctx context.Context, id int) ([]MovieDetails, error) {
+	var out pagedResults
+	err := c.get(ctx, "/movie/"+strconv.Itoa(id)+"/similar", nil, &out)
+	return out.Results, err
+}
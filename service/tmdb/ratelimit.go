@@ -0,0 +1,55 @@
+package tmdb
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TokenBucket is a simple fixed-window token bucket: it allows up to
+// capacity operations per window and blocks callers once the bucket is
+// empty until the window resets.
+type TokenBucket struct {
+	mu       sync.Mutex
+	capacity int
+	tokens   int
+	window   time.Duration
+	resetAt  time.Time
+}
+
+// NewTokenBucket creates a bucket allowing capacity operations per window.
+func NewTokenBucket(capacity int, window time.Duration) *TokenBucket {
+	return &TokenBucket{
+		capacity: capacity,
+		tokens:   capacity,
+		window:   window,
+		resetAt:  time.Now().Add(window),
+	}
+}
+
+// Wait blocks until a token is available or ctx is cancelled.
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		if now.After(b.resetAt) {
+			b.tokens = b.capacity
+			b.resetAt = now.Add(b.window)
+		}
+		if b.tokens > 0 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		wait := b.resetAt.Sub(now)
+		b.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package tmdb
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketAllowsBurstUpToCapacity(t *testing.T) {
+	b := NewTokenBucket(3, time.Minute)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if err := b.Wait(ctx); err != nil {
+			t.Fatalf("Wait() #%d: %v", i, err)
+		}
+	}
+}
+
+func TestTokenBucketBlocksUntilWindowResets(t *testing.T) {
+	b := NewTokenBucket(1, 20*time.Millisecond)
+	ctx := context.Background()
+
+	if err := b.Wait(ctx); err != nil {
+		t.Fatalf("first Wait(): %v", err)
+	}
+
+	start := time.Now()
+	if err := b.Wait(ctx); err != nil {
+		t.Fatalf("second Wait(): %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Fatalf("second Wait() returned after %v, expected to block for close to the window", elapsed)
+	}
+}
+
+func TestTokenBucketRespectsContextCancellation(t *testing.T) {
+	b := NewTokenBucket(1, time.Hour)
+	ctx := context.Background()
+	if err := b.Wait(ctx); err != nil {
+		t.Fatalf("first Wait(): %v", err)
+	}
+
+	cancelCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+
+	if err := b.Wait(cancelCtx); err == nil {
+		t.Fatal("Wait() with an exhausted bucket and a short deadline should return an error")
+	}
+}